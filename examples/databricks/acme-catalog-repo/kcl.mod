@@ -0,0 +1,9 @@
+[package]
+name = "acme-catalog"
+edition = "v0.11.2"
+version = "0.2.1-alpha"
+
+[dependencies]
+acme-product = { path = "../acme-product-repo", version = "0.2.1-alpha" }
+cdmesh-api = { path = "../../..", version = "0.2.1-alpha" }
+databricks-components = { path = "../databricks-components-repo", version = "0.2.1-alpha" }
@@ -5,4 +5,4 @@ version = "0.2.1-alpha"
 
 [dependencies]
 acme-mesh = { path = "../acme-mesh-repo", version = "0.2.1-alpha" }
-cdmesh-api = { path = "../../../api", version = "0.2.1-alpha" }
+cdmesh-api = { path = "../../..", version = "0.2.1-alpha" }
@@ -5,4 +5,4 @@ version = "0.2.1-alpha"
 
 [dependencies]
 acme-org = { path = "../acme-org-repo", version = "0.2.1-alpha" }
-cdmesh-api = { path = "../../../api", version = "0.2.1-alpha" }
+cdmesh-api = { path = "../../..", version = "0.2.1-alpha" }
@@ -4,4 +4,4 @@ edition = "v0.11.2"
 version = "0.2.1-alpha"
 
 [dependencies]
-cdmesh-api = { path = "../../../api", version = "0.2.1-alpha" }
+cdmesh-api = { path = "../../..", version = "0.2.1-alpha" }
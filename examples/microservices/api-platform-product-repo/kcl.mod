@@ -4,6 +4,6 @@ edition = "v0.11.2"
 version = "0.2.1-alpha"
 
 [dependencies]
-cdmesh-api = { path = "../../../api", version = "0.2.1-alpha" }
+cdmesh-api = { path = "../../..", version = "0.2.1-alpha" }
 identity-domain = { path = "../identity-domain-repo", version = "0.2.1-alpha" }
 kubernetes-components = { path = "../kubernetes-components-repo", version = "0.2.1-alpha" }
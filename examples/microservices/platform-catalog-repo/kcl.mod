@@ -0,0 +1,10 @@
+[package]
+name = "platform-catalog"
+edition = "v0.11.2"
+version = "0.2.1-alpha"
+
+[dependencies]
+api-platform-product = { path = "../api-platform-product-repo", version = "0.2.1-alpha" }
+cdmesh-api = { path = "../../..", version = "0.2.1-alpha" }
+identity-domain = { path = "../identity-domain-repo", version = "0.2.1-alpha" }
+kubernetes-components = { path = "../kubernetes-components-repo", version = "0.2.1-alpha" }
@@ -0,0 +1,129 @@
+#!/usr/bin/env bash
+# Scaffold a new mesh repository for one level of the hierarchy:
+#
+#   organization  <- mesh  <- domain  <- product      component (template catalog)
+#
+# The repo gets a kcl.mod depending on cdmesh-api and on its parent repo, and a
+# discovery/<id>.k declaring the node with its parent reference (organizationId,
+# meshId, domainId) taken from the parent repo, a default "dev" deployment and
+# the owner (given, else the owner declared by the parent node). It compiles as
+# generated and passes the Catalog integrity checks. A failed run removes the
+# partially written repo.
+#
+# Usage: scripts/init.sh <level> <id> [parent repo] [owner] [dir]
+#   level   organization | mesh | domain | product | component
+#   parent  repo of the parent node (required for mesh, domain and product)
+#   owner   owner team (default: the owner declared by the parent node)
+#   dir     repo to create (default: ./<id>-repo)
+set -euo pipefail
+
+LEVEL="${1:?level required: organization | mesh | domain | product | component}"
+ID="${2:?id required}"
+PARENT="${3:-}"
+OWNER="${4:-}"
+DIR="${5:-$ID-repo}"
+ROOT="$(cd "$(dirname "$0")/.." && pwd)"
+
+fail() { echo "init: $*" >&2; exit 1; }
+
+# Value of `key = "value"` in the [package] section of kcl.mod $1.
+package() {
+    sed -n "/^\[package\]/,/^\[/s/^$2 = \"\(.*\)\"/\1/p" "$1/kcl.mod" | head -1
+}
+
+# "customer-domain" -> "customerDomain" / "Customer Domain" / "customer_domain".
+variable() { echo "$1" | sed -E 's/[-_]([a-z0-9])/\U\1/g'; }
+title() { echo "$1" | sed -E 's/[-_]+/ /g; s/\b([a-z])/\U\1/g'; }
+module() { echo "$1" | tr -- '-' '_'; }
+
+case "$LEVEL" in
+    organization) PARENT_TYPE=""; PARENT_FIELD=""; PARENT_ALIAS="" ;;
+    mesh) PARENT_TYPE="Organization"; PARENT_FIELD="organizationId"; PARENT_ALIAS="org" ;;
+    domain) PARENT_TYPE="Mesh"; PARENT_FIELD="meshId"; PARENT_ALIAS="mesh" ;;
+    product) PARENT_TYPE="Domain"; PARENT_FIELD="domainId"; PARENT_ALIAS="domain" ;;
+    component) PARENT_TYPE=""; PARENT_FIELD=""; PARENT_ALIAS="" ;;
+    *) fail "unknown level: $LEVEL (organization, mesh, domain, product, component)" ;;
+esac
+
+[[ "$ID" =~ ^[a-z][a-z0-9-]*$ ]] || fail "id must be kebab-case: $ID"
+[ ! -e "$DIR" ] || fail "$DIR already exists"
+if [ -n "$PARENT_TYPE" ]; then
+    [ -n "$PARENT" ] || fail "a $LEVEL requires its parent $PARENT_TYPE repo"
+    [ -f "$PARENT/kcl.mod" ] || fail "parent repo has no kcl.mod: $PARENT"
+elif [ -n "$PARENT" ]; then
+    fail "a $LEVEL has no parent repo"
+fi
+
+# From here on the repo is ours: remove it unless the scaffold completes.
+trap 'rm -rf "$DIR"' EXIT
+mkdir -p "$DIR/discovery"
+DIR="$(cd "$DIR" && pwd)"
+API_VERSION="$(package "$ROOT" version)"
+API_DEP="cdmesh-api = { path = \"$(realpath --relative-to="$DIR" "$ROOT")\", version = \"$API_VERSION\" }"
+PARENT_DEP=""
+PARENT_IMPORT=""
+PARENT_REF=""
+
+if [ -n "$PARENT_TYPE" ]; then
+    PARENT="$(cd "$PARENT" && pwd)"
+    # Parent node: `<variable> = [alias.]<ParentType> {` in the parent discovery/ files.
+    PATTERN="^([A-Za-z_][A-Za-z0-9_]*) = ([A-Za-z_][A-Za-z0-9_]*\.)?$PARENT_TYPE \{"
+    PARENT_FILE="$(grep -lE "$PATTERN" "$PARENT"/discovery/*.k 2>/dev/null | head -1)" \
+        || fail "no $PARENT_TYPE declared in $PARENT/discovery"
+    PARENT_VAR="$(sed -nE "s/$PATTERN.*/\1/p" "$PARENT_FILE" | head -1)"
+    # Inherit the owner declared in the parent node block.
+    [ -n "$OWNER" ] || OWNER="$(sed -nE "/$PATTERN/,/^\}/s/^    owner = \"(.*)\"/\1/p" "$PARENT_FILE" | head -1)"
+    PARENT_NAME="$(package "$PARENT" name)"
+    PARENT_DEP="$PARENT_NAME = { path = \"$(realpath --relative-to="$DIR" "$PARENT")\", version = \"$(package "$PARENT" version)\" }"
+    PARENT_IMPORT="import $(module "$PARENT_NAME").discovery.$(basename "$PARENT_FILE" .k) as $PARENT_ALIAS"
+    PARENT_REF="    $PARENT_FIELD = $PARENT_ALIAS.$PARENT_VAR.id"
+    # Share the parent's cdmesh-api dependency (path or OCI) to avoid version conflicts.
+    PARENT_API="$(grep -E '^cdmesh-api = ' "$PARENT/kcl.mod" || true)"
+    if [[ "$PARENT_API" =~ path\ =\ \"([^\"]*)\" ]]; then
+        API_DEP="${PARENT_API/\"${BASH_REMATCH[1]}\"/\"$(realpath --relative-to="$DIR" "$PARENT/${BASH_REMATCH[1]}")\"}"
+    elif [ -n "$PARENT_API" ]; then
+        API_DEP="$PARENT_API"
+    fi
+fi
+
+cat > "$DIR/kcl.mod" <<EOF
+[package]
+name = "$ID"
+edition = "v0.11.2"
+version = "0.1.0"
+
+[dependencies]
+$(printf '%s\n' "$PARENT_DEP" "$API_DEP" | sed '/^$/d' | sort)
+EOF
+
+case "$LEVEL" in
+    organization) IMPORT="import cdmesh_api.discovery.organization as org"; TYPE="org.Organization" ;;
+    mesh) IMPORT="import cdmesh_api.discovery.mesh as meshes"; TYPE="meshes.Mesh" ;;
+    domain) IMPORT="import cdmesh_api.discovery.domain as domains"; TYPE="domains.Domain" ;;
+    product) IMPORT="import cdmesh_api.discovery.product as prod"; TYPE="prod.Product" ;;
+    component) IMPORT="import cdmesh_api.discovery.component as comp"; TYPE="comp.Component" ;;
+esac
+
+{
+    echo "import cdmesh_api.deploy.spec as deploy"
+    echo "$IMPORT"
+    [ -z "$PARENT_IMPORT" ] || printf '\n%s\n' "$PARENT_IMPORT"
+    echo
+    echo "$(variable "$ID") = $TYPE {"
+    [ -z "$PARENT_REF" ] || printf '%s\n\n' "$PARENT_REF"
+    echo "    id = \"$ID\""
+    echo "    name = \"$(title "$ID")\""
+    if [ "$LEVEL" = "component" ]; then
+        echo "    description = \"TODO: what this component template does\""
+        echo "    kind = \"transformation\""
+    fi
+    [ -z "$OWNER" ] || echo "    owner = \"$OWNER\""
+    echo
+    echo "    deployment = deploy.DeploymentSpec {"
+    echo "        environment = \"dev\""
+    echo "    }"
+    echo "}"
+} > "$DIR/discovery/$(module "$ID").k"
+
+trap - EXIT
+echo "Created $LEVEL repo $DIR"
@@ -0,0 +1,53 @@
+#!/usr/bin/env bash
+# Publish a module repo (e.g. a component catalog) to an OCI registry under its
+# kcl.mod name and version.
+#
+# Consumers of a published module cannot resolve its path dependencies
+# (`name = { path = "../..", version = "X" }`, as used between the example
+# repos). They are rewritten to the OCI tag `oci://<registry>/<name>` X in a
+# copy of the repo before pushing, so the dependencies must already be
+# published to the same registry. Path dependencies without a version are
+# rejected.
+#
+# Only the files tracked by git are published (with their working tree
+# content), so untracked files such as patches or .cdmesh/ exports never end up
+# in the module.
+#
+# Usage: scripts/publish.sh <repo> [registry] [plain_http]
+#   registry    registry and namespace (default: localhost:5000/cdmesh)
+#   plain_http  "on" for registries without TLS (default), "off" for TLS registries such as ghcr.io
+set -euo pipefail
+
+REPO="${1:?repo required}"
+REGISTRY="${2:-localhost:5000/cdmesh}"
+export OCI_REG_PLAIN_HTTP="${3:-on}"
+WORK="$(mktemp -d)"
+trap 'rm -rf "$WORK"' EXIT
+
+fail() { echo "publish: $*" >&2; exit 1; }
+
+# Lines of the [dependencies] section of kcl.mod.
+dependencies() { sed -n '/^\[dependencies\]/,/^\[/p' kcl.mod; }
+
+PATH_DEP='^\([A-Za-z0-9_-]*\) = { *path = "[^"]*", *version = "\([^"]*\)" *}$'
+
+[ -f "$REPO/kcl.mod" ] || fail "$REPO has no kcl.mod"
+git -C "$REPO" rev-parse --git-dir > /dev/null 2>&1 || fail "$REPO is not in a git repository"
+git -C "$REPO" ls-files -z | tar -C "$REPO" --null -T - -cf - | tar -C "$WORK" -xf -
+cd "$WORK"
+[ -f kcl.mod ] || fail "$REPO does not track its kcl.mod"
+rm -f kcl.mod.lock
+NAME="$(sed -n '/^\[package\]/,/^\[/s/^name = "\(.*\)"/\1/p' kcl.mod | head -1)"
+
+DEPS="$(dependencies | sed -n "s/$PATH_DEP/\1 \2/p")"
+sed -i "/^\[dependencies\]/,/^\[/{/$PATH_DEP/d}" kcl.mod
+UNVERSIONED="$(dependencies | sed -n 's/^\([A-Za-z0-9_-]*\) = {.*path *=.*/\1/p' | xargs)"
+[ -z "$UNVERSIONED" ] || fail "$NAME has path dependencies without a version: $UNVERSIONED"
+
+while read -r dep version; do
+    [ -n "$dep" ] || continue
+    echo "==> $dep: path -> oci://$REGISTRY/$dep $version"
+    kcl mod add "oci://$REGISTRY/$dep" --tag "$version" || fail "$dep $version is not published to $REGISTRY"
+done <<< "$DEPS"
+
+kcl mod push "oci://$REGISTRY/$NAME"
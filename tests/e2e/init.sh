@@ -0,0 +1,94 @@
+#!/usr/bin/env bash
+# End-to-end test of the repo scaffolding generator (scripts/init.sh, see `just init`):
+#
+#   1. generate an organization, mesh, domain, product and component repo chain,
+#      and check that failed generations leave nothing behind
+#   2. compile every generated repo as is
+#   3. assemble them into one Catalog and run the cross-repo integrity checks
+#
+# Usage: tests/e2e/init.sh
+set -euo pipefail
+
+ROOT="$(cd "$(dirname "$0")/../.." && pwd)"
+WORK="$(mktemp -d)"
+trap 'rm -rf "$WORK"' EXIT
+
+export KCL_PKG_PATH="$WORK/packages"   # isolated module cache
+
+API_VERSION="$(sed -n 's/^version = "\(.*\)"/\1/p' "$ROOT/kcl.mod")"
+
+step() { echo "==> $*"; }
+fail() { echo "FAIL: $*" >&2; exit 1; }
+
+cd "$WORK"
+
+step "generate the repo chain"
+"$ROOT/scripts/init.sh" organization globex
+"$ROOT/scripts/init.sh" mesh globex-mesh globex-repo platform-team
+"$ROOT/scripts/init.sh" domain sales-domain globex-mesh-repo sales-team
+"$ROOT/scripts/init.sh" product sales-orders sales-domain-repo
+"$ROOT/scripts/init.sh" component orders-enrich "" platform-team
+
+step "reject a product without its parent domain repo"
+if "$ROOT/scripts/init.sh" product orphan 2>/dev/null; then
+    fail "product generated without a parent repo"
+fi
+[ ! -e orphan-repo ] || fail "failed generation left orphan-repo behind"
+
+step "remove the repo when generation fails after writing"
+if "$ROOT/scripts/init.sh" product misplaced globex-mesh-repo 2> misplaced.err; then
+    fail "product generated under a mesh repo"
+fi
+grep -q "^init: no Domain declared in $WORK/globex-mesh-repo/discovery$" misplaced.err \
+    || fail "unexpected error: $(cat misplaced.err)"
+[ ! -e misplaced-repo ] || fail "failed generation left misplaced-repo behind"
+
+step "compile every generated repo"
+for repo in globex globex-mesh sales-domain sales-orders orders-enrich; do
+    (cd "$repo-repo" && kcl run "discovery/${repo//-/_}.k" > /dev/null) || fail "$repo-repo does not compile"
+done
+grep -q 'domainId = domain.salesDomain.id' sales-orders-repo/discovery/sales_orders.k \
+    || fail "product is not wired to its domain"
+grep -q 'owner = "sales-team"' sales-orders-repo/discovery/sales_orders.k \
+    || fail "product does not inherit the owner of its domain"
+grep -q 'owner = "platform-team"' globex-mesh-repo/discovery/globex_mesh.k \
+    || fail "mesh does not keep its given owner"
+
+step "check cross-repo integrity in a Catalog"
+mkdir catalog-repo
+cat > catalog-repo/kcl.mod <<EOF
+[package]
+name = "catalog"
+edition = "v0.11.2"
+version = "0.1.0"
+
+[dependencies]
+cdmesh-api = { path = "$ROOT", version = "$API_VERSION" }
+globex = { path = "../globex-repo", version = "0.1.0" }
+globex-mesh = { path = "../globex-mesh-repo", version = "0.1.0" }
+orders-enrich = { path = "../orders-enrich-repo", version = "0.1.0" }
+sales-domain = { path = "../sales-domain-repo", version = "0.1.0" }
+sales-orders = { path = "../sales-orders-repo", version = "0.1.0" }
+EOF
+cat > catalog-repo/mesh.k <<'KCL'
+import cdmesh_api.discovery.catalog as cat
+
+import globex.discovery.globex as org
+import globex_mesh.discovery.globex_mesh as mesh
+import sales_domain.discovery.sales_domain as domain
+import sales_orders.discovery.sales_orders as product
+import orders_enrich.discovery.orders_enrich as component
+
+catalog = cat.Catalog {
+    organizations = [org.globex]
+    meshes = [mesh.globexMesh]
+    domains = [domain.salesDomain]
+    products = [product.salesOrders]
+    components = [component.ordersEnrich]
+}
+
+productDomain = cat.find(catalog, "sales-orders").domainId
+KCL
+(cd catalog-repo && kcl run mesh.k -S productDomain) | grep -q 'sales-domain' || fail "catalog integrity checks failed"
+
+echo "OK: repo scaffolding"
@@ -0,0 +1,94 @@
+#!/usr/bin/env bash
+# End-to-end test of the component marketplace scaffold (discovery/marketplace.k,
+# see `just marketplace-scaffold`):
+#
+#   1. scaffold an instance of a template with port and nested config parameters,
+#      a SecretRef and a "${...}" string
+#   2. check the scaffold refuses to compile until its parameters are set
+#   3. compile it with every parameter set and check the strings survive as is
+#
+# Usage: tests/e2e/marketplace.sh
+set -euo pipefail
+
+ROOT="$(cd "$(dirname "$0")/../.." && pwd)"
+WORK="$(mktemp -d)"
+trap 'rm -rf "$WORK"' EXIT
+
+export KCL_PKG_PATH="$WORK/packages"   # isolated module cache
+
+API_VERSION="$(sed -n 's/^version = "\(.*\)"/\1/p' "$ROOT/kcl.mod")"
+
+step() { echo "==> $*"; }
+fail() { echo "FAIL: $*" >&2; exit 1; }
+
+mkdir -p "$WORK/catalog-repo"
+cd "$WORK/catalog-repo"
+cat > kcl.mod <<EOF2
+[package]
+name = "catalog"
+edition = "v0.11.2"
+version = "0.1.0"
+
+[dependencies]
+cdmesh-api = { path = "$ROOT", version = "$API_VERSION" }
+EOF2
+cat > mesh.k <<'KCL'
+import cdmesh_api.deploy.spec as deploy
+import cdmesh_api.discovery.catalog as cat
+import cdmesh_api.discovery.component as comp
+import cdmesh_api.discovery.config as cfg
+import cdmesh_api.discovery.marketplace
+import cdmesh_api.discovery.port
+import cdmesh_api.governance.secrets as sec
+
+kafkaSource = comp.Component {
+    id = "kafka-source"
+    name = "Kafka Source"
+    kind = "ingestion"
+    runtime = "databricks"
+    version = "1.0.0"
+    deployment = deploy.DeploymentSpec {environment = "dev"}
+    ports = [
+        port.Port {name = "events", description = "Events of " + "$" + "{env}", direction = "input", portType = "event", topic = "PARAM", messageFormat = "avro"}
+        port.Port {name = "bronze", direction = "output", portType = "data", format = "delta", catalog = "PARAM"}
+    ]
+    config = cfg.DatabricksConfig {
+        kafka = cfg.KafkaSource {bootstrapServers = "PARAM"}
+        streaming = cfg.Streaming {
+            checkpointInterval = "1m"
+            watermarks = [cfg.Watermark {port = "events", column = "PARAM", delay = "10s"}]
+        }
+        sparkConf = {"spark.hadoop.fs.s3a.secret.key": sec.SecretRef {store = "databricks", path = "lake", key = "s3"}}
+    }
+}
+
+catalog = cat.Catalog {
+    components = [kafkaSource]
+}
+
+componentSource = marketplace.scaffold(catalog, marketplace.SCAFFOLD)
+KCL
+
+step "scaffold an instance"
+kcl run mesh.k -S componentSource --format json -D template=kafka-source -D id=returns-ingest -D product=returns \
+    | jq -r '.. | strings' > returns_ingest.k
+grep -q '^#   events_topic$' returns_ingest.k || fail "scaffold does not list its parameters"
+grep -q 'import cdmesh_api.governance.secrets as sec' returns_ingest.k || fail "scaffold does not import secrets"
+
+step "refuse to compile with parameters unset"
+if kcl run returns_ingest.k > "$WORK/unset.log" 2>&1; then
+    fail "scaffold compiled with its parameters unset"
+fi
+grep -q 'events_topic' "$WORK/unset.log" || fail "unset parameter is not reported: $(cat "$WORK/unset.log")"
+
+step "compile with every parameter set"
+kcl run returns_ingest.k -S returnsIngest --format json \
+    -D events_topic=returns.events -D bronze_catalog=returns \
+    -D config_kafka_bootstrapServers=kafka:9092 -D config_streaming_watermarks_0_column=event_time \
+    | jq '.returnsIngest // .' > "$WORK/instance.json" || fail "scaffold does not compile"
+jq -e '.ports[0].topic == "returns.events" and .ports[0].description == "Events of ${env}"' "$WORK/instance.json" > /dev/null \
+    || fail "scaffolded values changed: $(cat "$WORK/instance.json")"
+jq -e '.config.streaming.watermarks[0].column == "event_time" and .config.sparkConf["spark.hadoop.fs.s3a.secret.key"].store == "databricks"' \
+    "$WORK/instance.json" > /dev/null || fail "scaffolded config changed: $(cat "$WORK/instance.json")"
+
+echo "OK: marketplace scaffold"
@@ -0,0 +1,123 @@
+#!/usr/bin/env bash
+# End-to-end test of the component catalog OCI workflow against a local
+# registry stand-in (registry:2, see `just registry-up`):
+#
+#   1. publish cdmesh-api, two versions of the databricks-components module and
+#      the kubernetes-components module (scripts/publish.sh rewrites their
+#      path dependency on cdmesh-api to its OCI tag)
+#   2. consume the components from a product module by version
+#   3. check version resolution and kcl.mod / kcl.mod.lock updates
+#
+# Usage: tests/e2e/oci.sh [registry]    (default: localhost:5000)
+set -euo pipefail
+
+REGISTRY="${1:-localhost:5000}"
+REPO="oci://${REGISTRY}/cdmesh"
+ROOT="$(cd "$(dirname "$0")/../.." && pwd)"
+WORK="$(mktemp -d)"
+trap 'rm -rf "$WORK"' EXIT
+
+export OCI_REG_PLAIN_HTTP=on           # local registry without TLS
+export KCL_PKG_PATH="$WORK/packages"   # isolated module cache
+
+API_VERSION="$(sed -n 's/^version = "\(.*\)"/\1/p' "$ROOT/kcl.mod")"
+
+step() { echo "==> $*"; }
+fail() { echo "FAIL: $*" >&2; exit 1; }
+
+# Copy of repo $1 at $2 as a git repo of its own (publish.sh packages tracked files only).
+checkout() {
+    cp -r "$1" "$2"
+    git -C "$2" init -q
+    git -C "$2" add -A
+}
+
+# Value of `kcl run main.k -S $1` (a string).
+value() {
+    kcl run main.k -S "$1" --format json | jq -r '.. | strings'
+}
+
+# Version of dependency $2 recorded in the lockfile of module $1.
+locked() {
+    grep -A3 "\[dependencies.$2\]" "$1/kcl.mod.lock" | sed -n 's/^ *version = "\(.*\)"/\1/p'
+}
+
+step "publish cdmesh-api ${API_VERSION}"
+mkdir -p "$WORK/cdmesh-api"
+git -C "$ROOT" ls-files -z -- . ':!examples' ':!tests' | tar -C "$ROOT" --null -T - -cf - | tar -C "$WORK/cdmesh-api" -xf -
+(cd "$WORK/cdmesh-api" && kcl mod push "$REPO/cdmesh-api")
+
+step "publish databricks-components 0.2.1-alpha and 0.3.0"
+checkout "$ROOT/examples/databricks/databricks-components-repo" "$WORK/components"
+echo "not part of the module" > "$WORK/components/REVIEW_DIFF.patch"
+"$ROOT/scripts/publish.sh" "$WORK/components" "$REGISTRY/cdmesh"
+grep -q '^cdmesh-api = { path' "$WORK/components/kcl.mod" || fail "publish changed the kcl.mod of the repo"
+sed -i 's/^version = ".*"/version = "0.3.0"/' "$WORK/components/kcl.mod"
+sed -i 's/^    version = "2.0.0"/    version = "3.0.0"/' "$WORK/components/transform/delta.k"
+"$ROOT/scripts/publish.sh" "$WORK/components" "$REGISTRY/cdmesh"
+
+step "publish kubernetes-components 0.2.1-alpha"
+"$ROOT/scripts/publish.sh" "$ROOT/examples/microservices/kubernetes-components-repo" "$REGISTRY/cdmesh"
+
+step "reject path dependencies without a version"
+checkout "$ROOT/examples/microservices/kubernetes-components-repo" "$WORK/unversioned"
+sed -i 's/^cdmesh-api = .*/cdmesh-api = { path = "..\/..\/.." }/' "$WORK/unversioned/kcl.mod"
+if "$ROOT/scripts/publish.sh" "$WORK/unversioned" "$REGISTRY/cdmesh" 2> "$WORK/unversioned.err"; then
+    fail "a module with an unversioned path dependency was published"
+fi
+grep -qx 'publish: kubernetes-components has path dependencies without a version: cdmesh-api' "$WORK/unversioned.err" \
+    || fail "unexpected error: $(cat "$WORK/unversioned.err")"
+
+step "consume databricks-components 0.2.1-alpha from a product"
+mkdir -p "$WORK/product"
+cd "$WORK/product"
+kcl mod init orders-product
+cd orders-product
+cat > main.k <<'KCL'
+import cdmesh_api.deploy.spec as deploy
+import cdmesh_api.discovery.component as comp
+import databricks_components.transform.delta as transform
+import kubernetes_components.service.api_gateway as gateway
+
+deltaTemplate = transform.databricksDeltaTransform
+
+ordersTransform = comp.Component {
+    id = "orders-transform"
+    name = "Orders Transform"
+    kind = deltaTemplate.kind
+    template = deltaTemplate.id
+    runtime = deltaTemplate.runtime
+    productId = "orders"
+    reusable = False
+    deployment = deploy.DeploymentSpec {
+        environment = "dev"
+    }
+}
+
+templateVersion = deltaTemplate.version
+gatewayVersion = gateway.apiGatewayTemplate.version
+KCL
+kcl mod add "$REPO/cdmesh-api" --tag "$API_VERSION"
+kcl mod add "$REPO/databricks-components" --tag 0.2.1-alpha
+[ "$(locked . databricks-components)" = "0.2.1-alpha" ] || fail "lockfile does not pin databricks-components 0.2.1-alpha"
+[ "$(value templateVersion)" = "2.0.0" ] || fail "0.2.1-alpha did not resolve template version 2.0.0"
+[ -z "$(find "$KCL_PKG_PATH" -name REVIEW_DIFF.patch)" ] || fail "an untracked file was published"
+
+step "consume kubernetes-components 0.2.1-alpha from the same product"
+kcl mod add "$REPO/kubernetes-components" --tag 0.2.1-alpha
+[ "$(locked . kubernetes-components)" = "0.2.1-alpha" ] || fail "lockfile does not pin kubernetes-components 0.2.1-alpha"
+[ "$(value gatewayVersion)" = "1.0.0" ] || fail "kubernetes-components did not resolve the api-gateway template"
+
+step "upgrade the product to databricks-components 0.3.0"
+kcl mod add "$REPO/databricks-components" --tag 0.3.0
+grep -q 'tag = "0\.3\.0"' kcl.mod || fail "kcl.mod does not reference databricks-components 0.3.0"
+[ "$(locked . databricks-components)" = "0.3.0" ] || fail "lockfile was not updated to databricks-components 0.3.0"
+[ "$(value templateVersion)" = "3.0.0" ] || fail "0.3.0 did not resolve template version 3.0.0"
+
+step "reject unpublished versions"
+if kcl mod add "$REPO/databricks-components" --tag 9.9.9 2>/dev/null; then
+    fail "unpublished version 9.9.9 was resolved"
+fi
+[ "$(locked . databricks-components)" = "0.3.0" ] || fail "failed resolution changed the lockfile"
+
+echo "OK: OCI component workflow"